@@ -0,0 +1,142 @@
+package zig
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"unsafe"
+)
+
+/*
+#include "zig.h"
+*/
+import "C"
+
+// Asset is a single image or sound clip referenced by a MediaNode found
+// while decoding an Entry's Nodes, keyed by that MediaNode's Position in
+// Subbook.Assets.
+type Asset struct {
+	Kind MediaKind
+
+	// Image holds a decoded bitmap for Kind MonoGraphic/ColorGraphic, when
+	// Data happened to be in a format image.Decode recognizes. It is nil for
+	// Kind Wave/Mpeg, and for graphics in a disc-native encoding Go's image
+	// package doesn't have a decoder for.
+	Image image.Image
+
+	// Data holds the raw bytes eb_read_binary returned at this asset's
+	// Position.
+	Data []byte
+}
+
+// loadAssets resolves every MediaNode referenced by subbook's entries or
+// menu (logos and intro screens are commonly hung off menu pages rather
+// than entries) into an Asset, gated by which of graphics/audio the caller
+// asked for.
+func (bc *bookContext) loadAssets(subbook Subbook, sc *subbookContext, graphics, audio bool) (map[Position]Asset, error) {
+	wanted := make(map[Position]MediaKind)
+	want := func(nodes []Node) {
+		walkNodes(nodes, func(n Node) {
+			media, ok := n.(MediaNode)
+			if !ok {
+				return
+			}
+
+			switch media.Kind {
+			case MediaKindMonoGraphic, MediaKindColorGraphic:
+				if !graphics {
+					return
+				}
+			case MediaKindWave, MediaKindMpeg:
+				if !audio {
+					return
+				}
+			}
+
+			wanted[Position{Page: media.Page, Offset: media.Offset}] = media.Kind
+		})
+	}
+
+	for _, entry := range subbook.Entries {
+		want(entry.Nodes)
+	}
+
+	if subbook.Menu != nil {
+		if err := bc.wantMenuAssets(subbook.Menu, sc, want); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(wanted) == 0 {
+		return nil, nil
+	}
+
+	assets := make(map[Position]Asset, len(wanted))
+	for pos, kind := range wanted {
+		data, err := bc.loadBinary(pos.toEB())
+		if err != nil {
+			return nil, err
+		}
+
+		asset := Asset{Kind: kind, Data: data}
+		if kind == MediaKindMonoGraphic || kind == MediaKindColorGraphic {
+			if img, _, err := image.Decode(bytes.NewReader(data)); err == nil {
+				asset.Image = img
+			}
+		}
+
+		assets[pos] = asset
+	}
+
+	return assets, nil
+}
+
+// wantMenuAssets walks menu's tree, re-decoding each page's content (a
+// MenuNode only retains its flattened Title, not the Nodes loadMenuNode
+// decoded it from) and feeding any MediaNodes found to want.
+func (bc *bookContext) wantMenuAssets(menu *MenuNode, sc *subbookContext, want func([]Node)) error {
+	raw, err := bc.loadContent(menu.Position.toEB(), blockTypeText, sc)
+	if err != nil {
+		return err
+	}
+
+	_, nodes := decodeNodes(raw)
+	want(nodes)
+
+	for i := range menu.Children {
+		if err := bc.wantMenuAssets(&menu.Children[i], sc, want); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadBinary reads the raw bytes eb_read_binary returns at position,
+// growing bc.buffer as needed the same way loadContent does for text.
+func (bc *bookContext) loadBinary(position C.EB_Position) ([]byte, error) {
+	for {
+		var (
+			data     = (*C.char)(unsafe.Pointer(&bc.buffer[0]))
+			dataSize = (C.size_t)(len(bc.buffer))
+			dataUsed C.ssize_t
+		)
+
+		if err := wrapEbError("eb_seek_text", C.eb_seek_text(bc.book, &position)); err != nil {
+			return nil, err
+		}
+
+		if err := wrapEbError("eb_read_binary", C.eb_read_binary(bc.book, &position, dataSize, data, &dataUsed)); err != nil {
+			return nil, err
+		}
+
+		if dataUsed+8 >= (C.ssize_t)(dataSize) {
+			bc.buffer = make([]byte, dataSize*2)
+			continue
+		}
+
+		return C.GoBytes(unsafe.Pointer(data), C.int(dataUsed)), nil
+	}
+}
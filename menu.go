@@ -0,0 +1,70 @@
+package zig
+
+/*
+#include "zig.h"
+*/
+import "C"
+
+// maxMenuDepth bounds how far loadMenu follows EB_HOOK_BEGIN_REFERENCE
+// targets, as a guard against a menu page that (directly or indirectly)
+// references itself.
+const maxMenuDepth = 8
+
+// MenuNode is one page of a subbook's menu tree (eb_menu), built by decoding
+// its content like any other entry and following each reference it contains
+// as a child page.
+type MenuNode struct {
+	Title    string
+	Position Position
+	Children []MenuNode
+}
+
+func (bc *bookContext) loadMenu(sc *subbookContext) (*MenuNode, error) {
+	if C.eb_have_menu(bc.book) == 0 {
+		return nil, nil
+	}
+
+	var position C.EB_Position
+	if err := wrapEbError("eb_menu", C.eb_menu(bc.book, &position)); err != nil {
+		return nil, err
+	}
+
+	return bc.loadMenuNode(positionFromEB(position), sc, maxMenuDepth)
+}
+
+func (bc *bookContext) loadMenuNode(pos Position, sc *subbookContext, depth int) (*MenuNode, error) {
+	raw, err := bc.loadContent(pos.toEB(), blockTypeText, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	title, nodes := decodeNodes(raw)
+	node := &MenuNode{Title: title, Position: pos}
+
+	if depth <= 0 {
+		return node, nil
+	}
+
+	var refs []*ReferenceNode
+	walkNodes(nodes, func(n Node) {
+		if ref, ok := n.(*ReferenceNode); ok {
+			refs = append(refs, ref)
+		}
+	})
+
+	for _, ref := range refs {
+		childPos := Position{Page: ref.Page, Offset: ref.Offset}
+		if childPos == pos {
+			continue
+		}
+
+		child, err := bc.loadMenuNode(childPos, sc, depth-1)
+		if err != nil {
+			return nil, err
+		}
+
+		node.Children = append(node.Children, *child)
+	}
+
+	return node, nil
+}
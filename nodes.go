@@ -0,0 +1,291 @@
+package zig
+
+import "strings"
+
+// Node is one piece of structured markup decoded from a hooked EB text
+// stream. Entry.Text is the flattened plaintext of the same stream; Entry
+// uses Node to preserve the structure that Text discards.
+type Node interface {
+	isNode()
+}
+
+// TextNode is a run of plain text.
+type TextNode struct {
+	Text string
+}
+
+// ReferenceNode is the target of an EB_HOOK_BEGIN_REFERENCE/END_REFERENCE
+// pair, pointing at the heading text it wraps.
+type ReferenceNode struct {
+	Page   int
+	Offset int
+	Nodes  []Node
+}
+
+// CandidateNode wraps an EB_HOOK_BEGIN_CANDIDATE/END_CANDIDATE_GROUP pair.
+type CandidateNode struct {
+	Nodes []Node
+}
+
+// KeywordNode wraps an EB_HOOK_BEGIN_KEYWORD/END_KEYWORD pair.
+type KeywordNode struct {
+	Nodes []Node
+}
+
+// SubscriptNode wraps an EB_HOOK_BEGIN_SUBSCRIPT/END_SUBSCRIPT pair.
+type SubscriptNode struct {
+	Nodes []Node
+}
+
+// SuperscriptNode wraps an EB_HOOK_BEGIN_SUPERSCRIPT/END_SUPERSCRIPT pair.
+type SuperscriptNode struct {
+	Nodes []Node
+}
+
+// IndentNode records an EB_HOOK_SET_INDENT change in the stream.
+type IndentNode struct {
+	Level int
+}
+
+// MediaKind identifies the asset an EB hook referenced.
+type MediaKind int
+
+const (
+	MediaKindMonoGraphic MediaKind = iota
+	MediaKindColorGraphic
+	MediaKindWave
+	MediaKindMpeg
+)
+
+// MediaNode records a reference to an image or sound asset embedded in the
+// stream (EB_HOOK_BEGIN_MONO_GRAPHIC, _COLOR_GRAPHIC, _WAVE or _MPEG).
+type MediaNode struct {
+	Kind   MediaKind
+	Page   int
+	Offset int
+}
+
+func (TextNode) isNode()         {}
+func (*ReferenceNode) isNode()   {}
+func (*CandidateNode) isNode()   {}
+func (*KeywordNode) isNode()     {}
+func (*SubscriptNode) isNode()   {}
+func (*SuperscriptNode) isNode() {}
+func (IndentNode) isNode()       {}
+func (MediaNode) isNode()        {}
+
+// container is implemented by the Node types that hold child Nodes.
+type container interface {
+	Node
+	addChild(Node)
+}
+
+func (n *ReferenceNode) addChild(child Node)   { n.Nodes = append(n.Nodes, child) }
+func (n *CandidateNode) addChild(child Node)   { n.Nodes = append(n.Nodes, child) }
+func (n *KeywordNode) addChild(child Node)     { n.Nodes = append(n.Nodes, child) }
+func (n *SubscriptNode) addChild(child Node)   { n.Nodes = append(n.Nodes, child) }
+func (n *SuperscriptNode) addChild(child Node) { n.Nodes = append(n.Nodes, child) }
+
+// decodeNodes parses a raw EB text stream tagged by hookCallback into a tree
+// of Nodes, and separately flattens it into plain text (tags stripped, gaiji
+// markers left as-is). Unrecognized tags are left as literal text, a close
+// tag that doesn't match the innermost open node is dropped, and a node that
+// is never closed runs to the end of the stream, so partially-tagged
+// dictionaries still decode.
+func decodeNodes(raw string) (string, []Node) {
+	var (
+		root  []Node
+		stack []container
+	)
+
+	appendNode := func(n Node) {
+		if len(stack) == 0 {
+			root = append(root, n)
+		} else {
+			stack[len(stack)-1].addChild(n)
+		}
+	}
+
+	appendText := func(s string) {
+		if s == "" {
+			return
+		}
+		appendNode(TextNode{Text: s})
+	}
+
+	i := 0
+	for i < len(raw) {
+		start := strings.Index(raw[i:], "{{")
+		if start < 0 {
+			appendText(raw[i:])
+			break
+		}
+		start += i
+		appendText(raw[i:start])
+
+		end := strings.Index(raw[start:], "}}")
+		if end < 0 {
+			appendText(raw[start:])
+			break
+		}
+		end += start
+
+		tag := raw[start+2 : end]
+		i = end + 2
+
+		if strings.HasPrefix(tag, "n_") || strings.HasPrefix(tag, "w_") {
+			// Gaiji stub marker: part of the visible text.
+			appendText("{{" + tag + "}}")
+			continue
+		}
+
+		if strings.HasPrefix(tag, "/") {
+			name := tag[1:]
+			if len(stack) > 0 && tagClosesNode(stack[len(stack)-1], name) {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		name, attrs := parseTag(tag)
+		switch name {
+		case "ref":
+			node := &ReferenceNode{Page: attrs.int("page"), Offset: attrs.int("offset")}
+			appendNode(node)
+			stack = append(stack, node)
+		case "candidate":
+			node := &CandidateNode{}
+			appendNode(node)
+			stack = append(stack, node)
+		case "keyword":
+			node := &KeywordNode{}
+			appendNode(node)
+			stack = append(stack, node)
+		case "sub":
+			node := &SubscriptNode{}
+			appendNode(node)
+			stack = append(stack, node)
+		case "sup":
+			node := &SuperscriptNode{}
+			appendNode(node)
+			stack = append(stack, node)
+		case "indent":
+			appendNode(IndentNode{Level: attrs.int("level")})
+		case "nl":
+			appendText("\n")
+		case "jis", "gb2312":
+			// Charset-switch markers don't themselves carry content.
+		case "graphic":
+			kind := MediaKindMonoGraphic
+			if attrs.str("kind") == "color" {
+				kind = MediaKindColorGraphic
+			}
+			appendNode(MediaNode{Kind: kind, Page: attrs.int("page"), Offset: attrs.int("offset")})
+		case "wave":
+			appendNode(MediaNode{Kind: MediaKindWave, Page: attrs.int("page"), Offset: attrs.int("offset")})
+		case "mpeg":
+			appendNode(MediaNode{Kind: MediaKindMpeg, Page: attrs.int("page"), Offset: attrs.int("offset")})
+		default:
+			appendText("{{" + tag + "}}")
+		}
+	}
+
+	return flattenNodes(root), root
+}
+
+func tagClosesNode(n container, name string) bool {
+	switch n.(type) {
+	case *ReferenceNode:
+		return name == "ref"
+	case *CandidateNode:
+		return name == "candidate"
+	case *KeywordNode:
+		return name == "keyword"
+	case *SubscriptNode:
+		return name == "sub"
+	case *SuperscriptNode:
+		return name == "sup"
+	default:
+		return false
+	}
+}
+
+func flattenNodes(nodes []Node) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case TextNode:
+			sb.WriteString(n.Text)
+		case *ReferenceNode:
+			sb.WriteString(flattenNodes(n.Nodes))
+		case *CandidateNode:
+			sb.WriteString(flattenNodes(n.Nodes))
+		case *KeywordNode:
+			sb.WriteString(flattenNodes(n.Nodes))
+		case *SubscriptNode:
+			sb.WriteString(flattenNodes(n.Nodes))
+		case *SuperscriptNode:
+			sb.WriteString(flattenNodes(n.Nodes))
+		}
+	}
+
+	return sb.String()
+}
+
+// walkNodes calls visit for every Node in the tree, including the children
+// of container nodes, in document order. It's used by callers that need to
+// collect every ReferenceNode or MediaNode regardless of nesting, such as
+// menu traversal and asset collection.
+func walkNodes(nodes []Node, visit func(Node)) {
+	for _, n := range nodes {
+		visit(n)
+
+		switch n := n.(type) {
+		case *ReferenceNode:
+			walkNodes(n.Nodes, visit)
+		case *CandidateNode:
+			walkNodes(n.Nodes, visit)
+		case *KeywordNode:
+			walkNodes(n.Nodes, visit)
+		case *SubscriptNode:
+			walkNodes(n.Nodes, visit)
+		case *SuperscriptNode:
+			walkNodes(n.Nodes, visit)
+		}
+	}
+}
+
+// tagAttrs is a parsed set of "key=value" attributes from a tag's body.
+type tagAttrs map[string]string
+
+func (a tagAttrs) str(key string) string {
+	return a[key]
+}
+
+func (a tagAttrs) int(key string) int {
+	v := 0
+	for _, r := range a[key] {
+		if r < '0' || r > '9' {
+			return 0
+		}
+		v = v*10 + int(r-'0')
+	}
+	return v
+}
+
+// parseTag splits a tag body like "ref:page=12,offset=34" into its name and
+// attributes.
+func parseTag(tag string) (string, tagAttrs) {
+	name, rest, hasAttrs := strings.Cut(tag, ":")
+	attrs := make(tagAttrs)
+	if !hasAttrs {
+		return name, attrs
+	}
+
+	for _, pair := range strings.Split(rest, ",") {
+		key, value, _ := strings.Cut(pair, "=")
+		attrs[key] = value
+	}
+
+	return name, attrs
+}
@@ -0,0 +1,197 @@
+package zig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeNodesPlainText(t *testing.T) {
+	text, nodes := decodeNodes("hello world")
+
+	if text != "hello world" {
+		t.Errorf("text = %q, want %q", text, "hello world")
+	}
+
+	want := []Node{TextNode{Text: "hello world"}}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("nodes = %#v, want %#v", nodes, want)
+	}
+}
+
+func TestDecodeNodesGaijiMarkersAreLiteralText(t *testing.T) {
+	text, nodes := decodeNodes("a{{n_41}}b{{w_257}}c")
+
+	wantText := "a{{n_41}}b{{w_257}}c"
+	if text != wantText {
+		t.Errorf("text = %q, want %q", text, wantText)
+	}
+
+	want := []Node{
+		TextNode{Text: "a"},
+		TextNode{Text: "{{n_41}}"},
+		TextNode{Text: "b"},
+		TextNode{Text: "{{w_257}}"},
+		TextNode{Text: "c"},
+	}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("nodes = %#v, want %#v", nodes, want)
+	}
+}
+
+func TestDecodeNodesReference(t *testing.T) {
+	text, nodes := decodeNodes("see {{ref:page=12,offset=34}}also{{/ref}} here")
+
+	wantText := "see also here"
+	if text != wantText {
+		t.Errorf("text = %q, want %q", text, wantText)
+	}
+
+	if len(nodes) != 3 {
+		t.Fatalf("nodes = %#v, want 3 top-level nodes", nodes)
+	}
+
+	ref, ok := nodes[1].(*ReferenceNode)
+	if !ok {
+		t.Fatalf("nodes[1] = %#v, want *ReferenceNode", nodes[1])
+	}
+	if ref.Page != 12 || ref.Offset != 34 {
+		t.Errorf("ref = %+v, want Page=12 Offset=34", ref)
+	}
+
+	wantRefNodes := []Node{TextNode{Text: "also"}}
+	if !reflect.DeepEqual(ref.Nodes, wantRefNodes) {
+		t.Errorf("ref.Nodes = %#v, want %#v", ref.Nodes, wantRefNodes)
+	}
+}
+
+func TestDecodeNodesCandidateKeywordSubSup(t *testing.T) {
+	raw := "{{candidate}}a{{/candidate}}{{keyword}}b{{/keyword}}{{sub}}c{{/sub}}{{sup}}d{{/sup}}"
+	text, nodes := decodeNodes(raw)
+
+	if text != "abcd" {
+		t.Errorf("text = %q, want %q", text, "abcd")
+	}
+
+	if len(nodes) != 4 {
+		t.Fatalf("nodes = %#v, want 4 top-level nodes", nodes)
+	}
+	if _, ok := nodes[0].(*CandidateNode); !ok {
+		t.Errorf("nodes[0] = %#v, want *CandidateNode", nodes[0])
+	}
+	if _, ok := nodes[1].(*KeywordNode); !ok {
+		t.Errorf("nodes[1] = %#v, want *KeywordNode", nodes[1])
+	}
+	if _, ok := nodes[2].(*SubscriptNode); !ok {
+		t.Errorf("nodes[2] = %#v, want *SubscriptNode", nodes[2])
+	}
+	if _, ok := nodes[3].(*SuperscriptNode); !ok {
+		t.Errorf("nodes[3] = %#v, want *SuperscriptNode", nodes[3])
+	}
+}
+
+func TestDecodeNodesIndentAndNewline(t *testing.T) {
+	text, nodes := decodeNodes("a{{indent:level=2}}b{{nl}}c")
+
+	if text != "ab\nc" {
+		t.Errorf("text = %q, want %q", text, "ab\nc")
+	}
+
+	var found bool
+	for _, n := range nodes {
+		if in, ok := n.(IndentNode); ok {
+			found = true
+			if in.Level != 2 {
+				t.Errorf("IndentNode.Level = %d, want 2", in.Level)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("nodes = %#v, want an IndentNode{Level: 2}", nodes)
+	}
+}
+
+func TestDecodeNodesMedia(t *testing.T) {
+	raw := "{{graphic:kind=mono,page=1,offset=2}}{{/graphic}}" +
+		"{{graphic:kind=color,page=3,offset=4}}{{/graphic}}" +
+		"{{wave:page=5,offset=6}}{{/wave}}" +
+		"{{mpeg:page=7,offset=8}}{{/mpeg}}"
+
+	_, nodes := decodeNodes(raw)
+
+	want := []Node{
+		MediaNode{Kind: MediaKindMonoGraphic, Page: 1, Offset: 2},
+		MediaNode{Kind: MediaKindColorGraphic, Page: 3, Offset: 4},
+		MediaNode{Kind: MediaKindWave, Page: 5, Offset: 6},
+		MediaNode{Kind: MediaKindMpeg, Page: 7, Offset: 8},
+	}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("nodes = %#v, want %#v", nodes, want)
+	}
+}
+
+func TestDecodeNodesUnclosedTagStillFlattens(t *testing.T) {
+	// No {{/ref}} ever arrives, so the ReferenceNode is left open through
+	// the end of the stream; "b" ends up as its child rather than a
+	// sibling, but the flattened text reads the same either way.
+	text, _ := decodeNodes("a{{ref:page=1,offset=2}}b")
+
+	if text != "ab" {
+		t.Errorf("text = %q, want %q", text, "ab")
+	}
+}
+
+func TestDecodeNodesMismatchedCloseTagIsIgnored(t *testing.T) {
+	// The stray {{/sup}} doesn't close the open {{sub}}, so "b" stays inside
+	// it rather than leaking out, and the {{/sub}} that follows is what
+	// actually closes it.
+	text, nodes := decodeNodes("{{sub}}a{{/sup}}b{{/sub}}c")
+
+	if text != "abc" {
+		t.Errorf("text = %q, want %q", text, "abc")
+	}
+
+	if len(nodes) != 2 {
+		t.Fatalf("nodes = %#v, want 2 top-level nodes", nodes)
+	}
+
+	sub, ok := nodes[0].(*SubscriptNode)
+	if !ok {
+		t.Fatalf("nodes[0] = %#v, want *SubscriptNode", nodes[0])
+	}
+
+	wantSubNodes := []Node{TextNode{Text: "a"}, TextNode{Text: "b"}}
+	if !reflect.DeepEqual(sub.Nodes, wantSubNodes) {
+		t.Errorf("sub.Nodes = %#v, want %#v", sub.Nodes, wantSubNodes)
+	}
+}
+
+func TestDecodeNodesUnknownTagIsPreservedAsText(t *testing.T) {
+	text, _ := decodeNodes("a{{bogus:x=1}}b")
+
+	want := "a{{bogus:x=1}}b"
+	if text != want {
+		t.Errorf("text = %q, want %q", text, want)
+	}
+}
+
+func TestWalkNodesVisitsNestedChildren(t *testing.T) {
+	raw := "{{ref:page=1,offset=2}}{{candidate}}x{{graphic:kind=mono,page=9,offset=9}}{{/graphic}}{{/candidate}}{{/ref}}"
+	_, nodes := decodeNodes(raw)
+
+	var kinds []string
+	walkNodes(nodes, func(n Node) {
+		switch n.(type) {
+		case *ReferenceNode:
+			kinds = append(kinds, "ref")
+		case *CandidateNode:
+			kinds = append(kinds, "candidate")
+		case MediaNode:
+			kinds = append(kinds, "media")
+		}
+	})
+
+	want := []string{"ref", "candidate", "media"}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("walk order = %v, want %v", kinds, want)
+	}
+}
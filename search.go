@@ -0,0 +1,299 @@
+package zig
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+/*
+#include "zig.h"
+*/
+import "C"
+
+// SearchKind identifies one of EB's native search methods, for use with
+// Subbook.HasSearch.
+type SearchKind int
+
+const (
+	SearchKindWord SearchKind = iota
+	SearchKindEndword
+	SearchKindKeyword
+	SearchKindCross
+)
+
+const maxHitsPerBatch = 256
+
+// Hit is a single result from a Subbook Search* call. Text is read lazily,
+// since a search can return far more hits than a caller ultimately needs.
+type Hit struct {
+	Heading  string
+	Position Position
+
+	subbook *Subbook
+}
+
+// Text seeks to the hit's body and decodes it, on demand.
+func (h Hit) Text() (string, error) {
+	if h.subbook.live == nil {
+		entry, err := h.subbook.Seek(h.Position)
+		if err != nil {
+			return "", err
+		}
+
+		return entry.Text, nil
+	}
+
+	return h.subbook.readText(h.Position)
+}
+
+// HasSearch reports whether this subbook supports the given native search
+// kind (eb_have_word_search and friends).
+func (s *Subbook) HasSearch(kind SearchKind) bool {
+	bc := s.live
+	if bc == nil {
+		return false
+	}
+
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if err := wrapEbError("eb_set_subbook", C.eb_set_subbook(bc.book, s.code)); err != nil {
+		return false
+	}
+
+	switch kind {
+	case SearchKindWord:
+		return C.eb_have_word_search(bc.book) != 0
+	case SearchKindEndword:
+		return C.eb_have_endword_search(bc.book) != 0
+	case SearchKindKeyword:
+		return C.eb_have_keyword_search(bc.book) != 0
+	case SearchKindCross:
+		return C.eb_have_cross_search(bc.book) != 0
+	default:
+		return false
+	}
+}
+
+// SearchWord performs EB's forward word search for query.
+func (s *Subbook) SearchWord(query string) ([]Hit, error) {
+	sc, unlock, err := s.prepareSearch()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	bc := s.live
+	queryC, err := bc.newQueryC(query)
+	if err != nil {
+		return nil, err
+	}
+	defer C.free(unsafe.Pointer(queryC))
+
+	if err := wrapEbError("eb_search_word", C.eb_search_word(bc.book, queryC)); err != nil {
+		return nil, err
+	}
+
+	return s.collectHits(sc)
+}
+
+// SearchEndword performs EB's backward word search for query.
+func (s *Subbook) SearchEndword(query string) ([]Hit, error) {
+	sc, unlock, err := s.prepareSearch()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	bc := s.live
+	queryC, err := bc.newQueryC(query)
+	if err != nil {
+		return nil, err
+	}
+	defer C.free(unsafe.Pointer(queryC))
+
+	if err := wrapEbError("eb_search_endword", C.eb_search_endword(bc.book, queryC)); err != nil {
+		return nil, err
+	}
+
+	return s.collectHits(sc)
+}
+
+// SearchKeyword performs EB's keyword search: entries matching all of
+// queries, in any order.
+func (s *Subbook) SearchKeyword(queries []string) ([]Hit, error) {
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("zig: SearchKeyword requires at least one query")
+	}
+
+	sc, unlock, err := s.prepareSearch()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	bc := s.live
+	queriesC, free, err := bc.newQueriesC(queries)
+	if err != nil {
+		return nil, err
+	}
+	defer free()
+
+	if err := wrapEbError("eb_search_keyword", C.eb_search_keyword(bc.book, &queriesC[0], C.int(len(queriesC)))); err != nil {
+		return nil, err
+	}
+
+	return s.collectHits(sc)
+}
+
+// SearchCross performs EB's cross search: entries referencing all of
+// queries from their cross-search index.
+func (s *Subbook) SearchCross(queries []string) ([]Hit, error) {
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("zig: SearchCross requires at least one query")
+	}
+
+	sc, unlock, err := s.prepareSearch()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	bc := s.live
+	queriesC, free, err := bc.newQueriesC(queries)
+	if err != nil {
+		return nil, err
+	}
+	defer free()
+
+	if err := wrapEbError("eb_search_cross", C.eb_search_cross(bc.book, &queriesC[0], C.int(len(queriesC)))); err != nil {
+		return nil, err
+	}
+
+	return s.collectHits(sc)
+}
+
+// prepareSearch selects s within its live Book, builds the subbookContext
+// that carries its hook state (mirroring what loadSubbook does for a full
+// load), and locks bc for the duration of the caller's operation. Every
+// Subbook of one Open'd Book shares a single underlying EB_Book handle, so
+// selecting a subbook and then issuing a search or read against it must be
+// atomic with respect to other goroutines doing the same against a
+// different Subbook of the same Book. The returned unlock must be called
+// once the caller is done with bc (including, for a search, the whole of
+// collectHits) — callers that return hits for lazy reading (Hit.Text) are
+// fine to unlock before those later reads, since each one reacquires the
+// lock itself via readText.
+func (s *Subbook) prepareSearch() (*subbookContext, func(), error) {
+	bc := s.live
+	if bc == nil {
+		return nil, nil, fmt.Errorf("zig: search requires a Book opened with Open, not Load")
+	}
+
+	bc.mu.Lock()
+
+	if err := wrapEbError("eb_set_subbook", C.eb_set_subbook(bc.book, s.code)); err != nil {
+		bc.mu.Unlock()
+		return nil, nil, err
+	}
+
+	sc := &subbookContext{
+		codepointsWide:   make(map[int]bool),
+		codepointsNarrow: make(map[int]bool),
+		flags:            s.flags,
+		gaijiContext:     s.gaijiContext,
+	}
+
+	return sc, bc.mu.Unlock, nil
+}
+
+// readText decodes the body at pos using this subbook's already-open book
+// handle, without re-initializing and re-binding the book as Seek does.
+func (s *Subbook) readText(pos Position) (string, error) {
+	sc, unlock, err := s.prepareSearch()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	raw, err := s.live.loadContent(pos.toEB(), blockTypeText, sc)
+	if err != nil {
+		return "", err
+	}
+
+	text, _ := decodeNodes(raw)
+	return text, nil
+}
+
+// collectHits drains eb_hit_list for the search just issued against s's live
+// book, resolving each hit's heading text.
+func (s *Subbook) collectHits(sc *subbookContext) ([]Hit, error) {
+	bc := s.live
+
+	var hits []Hit
+	for {
+		var (
+			ebHits     [maxHitsPerBatch]C.EB_Hit
+			ebHitCount C.int
+		)
+
+		if err := wrapEbError("eb_hit_list", C.eb_hit_list(bc.book, maxHitsPerBatch, &ebHits[0], &ebHitCount)); err != nil {
+			return nil, err
+		}
+
+		if ebHitCount == 0 {
+			break
+		}
+
+		for i := 0; i < int(ebHitCount); i++ {
+			heading, err := bc.loadContent(ebHits[i].heading, blockTypeHeading, sc)
+			if err != nil {
+				return nil, err
+			}
+
+			hits = append(hits, Hit{
+				Heading:  heading,
+				Position: positionFromEB(ebHits[i].text),
+				subbook:  s,
+			})
+		}
+
+		if int(ebHitCount) < maxHitsPerBatch {
+			break
+		}
+	}
+
+	return hits, nil
+}
+
+func (bc *bookContext) newQueryC(query string) (*C.char, error) {
+	encoded, err := bc.encoder.String(query)
+	if err != nil {
+		return nil, fmt.Errorf("encoding query to EUC-JP: %w", err)
+	}
+
+	return C.CString(encoded), nil
+}
+
+func (bc *bookContext) newQueriesC(queries []string) ([]*C.char, func(), error) {
+	queriesC := make([]*C.char, len(queries))
+	for i, query := range queries {
+		queryC, err := bc.newQueryC(query)
+		if err != nil {
+			for _, c := range queriesC[:i] {
+				C.free(unsafe.Pointer(c))
+			}
+			return nil, nil, err
+		}
+
+		queriesC[i] = queryC
+	}
+
+	free := func() {
+		for _, c := range queriesC {
+			C.free(unsafe.Pointer(c))
+		}
+	}
+
+	return queriesC, free, nil
+}
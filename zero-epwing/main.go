@@ -31,6 +31,15 @@ type Book struct {
 	Subbooks []Subbook `json:"subbooks"`
 }
 
+type MenuNode struct {
+	Title    string     `json:"title"`
+	Children []MenuNode `json:"children,omitempty"`
+}
+
+type Menu struct {
+	Subbooks []*MenuNode `json:"subbooks"`
+}
+
 func outputEntries(bookSrc *zig.Book, path string, pretty bool) error {
 	bookDst := Book{
 		DiscCode: bookSrc.DiscCode,
@@ -121,6 +130,96 @@ func outputGaiji(bookSrc *zig.Book, gaiji16Dir, gaiji24Dir, gaiji30Dir, gaiji48D
 	return nil
 }
 
+func convertMenuNode(nodeSrc *zig.MenuNode) *MenuNode {
+	if nodeSrc == nil {
+		return nil
+	}
+
+	nodeDst := &MenuNode{Title: nodeSrc.Title}
+	for i := range nodeSrc.Children {
+		nodeDst.Children = append(nodeDst.Children, *convertMenuNode(&nodeSrc.Children[i]))
+	}
+
+	return nodeDst
+}
+
+func outputMenu(bookSrc *zig.Book, path string, pretty bool) error {
+	menuDst := Menu{}
+	for _, subbookSrc := range bookSrc.Subbooks {
+		menuDst.Subbooks = append(menuDst.Subbooks, convertMenuNode(subbookSrc.Menu))
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	if pretty {
+		data, err = json.MarshalIndent(menuDst, "", "\t")
+	} else {
+		data, err = json.Marshal(menuDst)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func assetKindLabel(kind zig.MediaKind) string {
+	switch kind {
+	case zig.MediaKindMonoGraphic:
+		return "mono"
+	case zig.MediaKindColorGraphic:
+		return "color"
+	case zig.MediaKindWave:
+		return "wave"
+	case zig.MediaKindMpeg:
+		return "mpeg"
+	default:
+		return "asset"
+	}
+}
+
+func outputAssets(bookSrc *zig.Book, assetsDir string) error {
+	for subbookIndex, subbook := range bookSrc.Subbooks {
+		for pos, asset := range subbook.Assets {
+			if asset.Image != nil {
+				assetPath := path.Join(assetsDir, fmt.Sprintf("%d_%d_%d_%s.png", subbookIndex, pos.Page, pos.Offset, assetKindLabel(asset.Kind)))
+				if err := func() error {
+					fp, err := os.Create(assetPath)
+					if err != nil {
+						return err
+					}
+
+					defer fp.Close()
+					return png.Encode(fp, asset.Image)
+				}(); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			ext := "bin"
+			switch asset.Kind {
+			case zig.MediaKindWave:
+				ext = "wav"
+			case zig.MediaKindMpeg:
+				ext = "mpg"
+			}
+
+			assetPath := path.Join(assetsDir, fmt.Sprintf("%d_%d_%d_%s.%s", subbookIndex, pos.Page, pos.Offset, assetKindLabel(asset.Kind), ext))
+			if err := ioutil.WriteFile(assetPath, asset.Data, 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func main() {
 	var (
 		gaiji16Dir    = flag.String("gaiji16-dir", "", "output directory for gaiji glyphs (size 16)")
@@ -129,6 +228,8 @@ func main() {
 		gaiji48Dir    = flag.String("gaiji48-dir", "", "output directory for gaiji glyphs (size 48)")
 		entriesPath   = flag.String("entries-path", "", "output path for dictionary entries")
 		entriesPretty = flag.Bool("entries-pretty", false, "pretty-print dictionary entries")
+		menuPath      = flag.String("menu-path", "", "output path for the dictionary menu tree")
+		assetsDir     = flag.String("assets-dir", "", "output directory for embedded image and sound assets")
 	)
 
 	flag.Usage = func() {
@@ -158,6 +259,12 @@ func main() {
 	if len(*gaiji48Dir) > 0 {
 		flags |= zig.LoadFlagsGaiji48
 	}
+	if len(*menuPath) > 0 {
+		flags |= zig.LoadFlagsMenu
+	}
+	if len(*assetsDir) > 0 {
+		flags |= zig.LoadFlagsGraphics | zig.LoadFlagsAudio
+	}
 
 	book, err := zig.Load(args[0], flags)
 	if err != nil {
@@ -173,4 +280,16 @@ func main() {
 	if err := outputGaiji(book, *gaiji16Dir, *gaiji24Dir, *gaiji30Dir, *gaiji48Dir); err != nil {
 		log.Fatal(err)
 	}
+
+	if len(*menuPath) > 0 {
+		if err := outputMenu(book, *menuPath, *entriesPretty); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if len(*assetsDir) > 0 {
+		if err := outputAssets(book, *assetsDir); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
@@ -4,11 +4,15 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"runtime/cgo"
+	"strings"
 	"sync"
 	"unsafe"
 
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/japanese"
+
+	"github.com/FooSoft/zero-epwing-go/zig/gaiji"
 )
 
 /*
@@ -43,43 +47,173 @@ const (
 	LoadFlagsGaiji24
 	LoadFlagsGaiji30
 	LoadFlagsGaiji48
-)
 
-var (
-	activeSubbookContext *subbookContext
-	activeSubbookLock    sync.Mutex
+	LoadFlagsMenu
+	LoadFlagsGraphics
+	LoadFlagsAudio
 )
 
-func setSubbookContext(sc *subbookContext) {
-	activeSubbookLock.Lock()
-	activeSubbookContext = sc
+// GaijiContext carries a dictionary's gaiji translation tables, mapping
+// narrow/wide font codepoints to the UTF-8 characters they represent.
+type GaijiContext = gaiji.Context
+
+// GaijiMatcher selects the GaijiContext to use for a subbook, typically by
+// inspecting its title. It returns nil if no context applies.
+type GaijiMatcher func(subbook Subbook) *GaijiContext
+
+var builtinGaijiContexts []*GaijiContext
+
+func init() {
+	contexts, err := gaiji.Builtin()
+	if err != nil {
+		panic(fmt.Sprintf("zig: failed to load builtin gaiji contexts: %s", err))
+	}
+
+	builtinGaijiContexts = contexts
 }
 
-func clearSubbookContext() {
-	activeSubbookContext = nil
-	activeSubbookLock.Unlock()
+// RegisterGaijiContext adds a GaijiContext to the built-in registry that
+// matchDefaultGaijiContext consults, in addition to the contexts this
+// package ships with.
+func RegisterGaijiContext(context *GaijiContext) {
+	builtinGaijiContexts = append(builtinGaijiContexts, context)
+}
+
+func matchDefaultGaijiContext(contexts []*GaijiContext, subbook Subbook) *GaijiContext {
+	for _, context := range contexts {
+		if strings.Contains(subbook.Title, context.Name) {
+			return context
+		}
+	}
+
+	return nil
 }
 
 type subbookContext struct {
 	codepointsWide   map[int]bool
 	codepointsNarrow map[int]bool
 	flags            LoadFlags
+	gaijiContext     *GaijiContext
+}
+
+// newContainer wraps sc in a cgo.Handle and returns it as the opaque
+// container pointer that eb_read_text/eb_read_heading thread through to
+// hookCallback. The returned release func must be called once EB is done
+// with the read (the handle is only valid until then). Passing state this
+// way, rather than through a package-global, is what lets two goroutines
+// read from two different books at once without racing on shared state.
+//
+// The handle is cast to a pointer by containerFromHandle (zig.c), not here:
+// eb_read_text/eb_read_heading bind their void* container parameter as a Go
+// unsafe.Pointer, and forming one directly from an arbitrary cgo.Handle
+// integer is not one of the patterns unsafe.Pointer documents as valid.
+func newContainer(sc *subbookContext) (unsafe.Pointer, func()) {
+	handle := cgo.NewHandle(sc)
+	return C.containerFromHandle(C.uintptr_t(handle)), handle.Delete
+}
+
+// subbookContextFromContainer recovers the subbookContext a container
+// pointer was created from via newContainer. It returns nil if container is
+// nil, which callback invocations that don't carry hook state (e.g. outside
+// a read) may legitimately pass.
+func subbookContextFromContainer(container unsafe.Pointer) *subbookContext {
+	if container == nil {
+		return nil
+	}
+
+	handle := cgo.Handle(uintptr(C.handleFromContainer(container)))
+	sc, _ := handle.Value().(*subbookContext)
+	return sc
 }
 
 //export hookCallback
 func hookCallback(book *C.EB_Book, appendix *C.EB_Appendix, container *C.void, hookCode C.EB_Hook_Code, argc C.int, argv *C.uint) C.EB_Error_Code {
+	sc := subbookContextFromContainer(unsafe.Pointer(container))
+	if sc == nil {
+		return C.EB_SUCCESS
+	}
+
 	var marker string
 	switch hookCode {
 	case C.EB_HOOK_NARROW_FONT:
-		activeSubbookContext.codepointsNarrow[int(*argv)] = true
-		if activeSubbookContext.flags&LoadFlagsStubGaiji != 0 {
-			marker = fmt.Sprintf("{{n_%d}}", *argv)
+		codepoint := int(*argv)
+		sc.codepointsNarrow[codepoint] = true
+		if glyph, ok := lookupGaiji(sc.gaijiContext, codepoint, fontTypeNarrow); ok {
+			marker = glyph
+		} else if sc.flags&LoadFlagsStubGaiji != 0 {
+			marker = fmt.Sprintf("{{n_%d}}", codepoint)
 		}
 	case C.EB_HOOK_WIDE_FONT:
-		activeSubbookContext.codepointsWide[int(*argv)] = true
-		if activeSubbookContext.flags&LoadFlagsStubGaiji != 0 {
-			marker = fmt.Sprintf("{{w_%d}}", *argv)
+		codepoint := int(*argv)
+		sc.codepointsWide[codepoint] = true
+		if glyph, ok := lookupGaiji(sc.gaijiContext, codepoint, fontTypeWide); ok {
+			marker = glyph
+		} else if sc.flags&LoadFlagsStubGaiji != 0 {
+			marker = fmt.Sprintf("{{w_%d}}", codepoint)
 		}
+
+	case C.EB_HOOK_BEGIN_REFERENCE:
+		args := hookArgs(argc, argv)
+		marker = fmt.Sprintf("{{ref:page=%d,offset=%d}}", args[0], args[1])
+	case C.EB_HOOK_END_REFERENCE:
+		marker = "{{/ref}}"
+
+	case C.EB_HOOK_BEGIN_CANDIDATE:
+		marker = "{{candidate}}"
+	case C.EB_HOOK_END_CANDIDATE_GROUP:
+		marker = "{{/candidate}}"
+
+	case C.EB_HOOK_BEGIN_KEYWORD:
+		marker = "{{keyword}}"
+	case C.EB_HOOK_END_KEYWORD:
+		marker = "{{/keyword}}"
+
+	case C.EB_HOOK_BEGIN_SUBSCRIPT:
+		marker = "{{sub}}"
+	case C.EB_HOOK_END_SUBSCRIPT:
+		marker = "{{/sub}}"
+
+	case C.EB_HOOK_BEGIN_SUPERSCRIPT:
+		marker = "{{sup}}"
+	case C.EB_HOOK_END_SUPERSCRIPT:
+		marker = "{{/sup}}"
+
+	case C.EB_HOOK_SET_INDENT:
+		args := hookArgs(argc, argv)
+		marker = fmt.Sprintf("{{indent:level=%d}}", args[len(args)-1])
+
+	case C.EB_HOOK_NEWLINE:
+		marker = "{{nl}}"
+
+	case C.EB_HOOK_NARROW_JISX0208:
+		marker = "{{jis:width=narrow}}"
+	case C.EB_HOOK_WIDE_JISX0208:
+		marker = "{{jis:width=wide}}"
+	case C.EB_HOOK_GB2312:
+		marker = "{{gb2312}}"
+
+	case C.EB_HOOK_BEGIN_MONO_GRAPHIC:
+		args := hookArgs(argc, argv)
+		marker = fmt.Sprintf("{{graphic:kind=mono,page=%d,offset=%d}}", args[0], args[1])
+	case C.EB_HOOK_END_MONO_GRAPHIC:
+		marker = "{{/graphic}}"
+	case C.EB_HOOK_BEGIN_COLOR_GRAPHIC:
+		args := hookArgs(argc, argv)
+		marker = fmt.Sprintf("{{graphic:kind=color,page=%d,offset=%d}}", args[0], args[1])
+	case C.EB_HOOK_END_COLOR_GRAPHIC:
+		marker = "{{/graphic}}"
+
+	case C.EB_HOOK_BEGIN_WAVE:
+		args := hookArgs(argc, argv)
+		marker = fmt.Sprintf("{{wave:page=%d,offset=%d}}", args[0], args[1])
+	case C.EB_HOOK_END_WAVE:
+		marker = "{{/wave}}"
+
+	case C.EB_HOOK_BEGIN_MPEG:
+		args := hookArgs(argc, argv)
+		marker = fmt.Sprintf("{{mpeg:page=%d,offset=%d}}", args[0], args[1])
+	case C.EB_HOOK_END_MPEG:
+		marker = "{{/mpeg}}"
 	}
 
 	if len(marker) > 0 {
@@ -91,6 +225,39 @@ func hookCallback(book *C.EB_Book, appendix *C.EB_Appendix, container *C.void, h
 	return C.EB_SUCCESS
 }
 
+// hookArgs decodes the argv array EB passes to a hook callback into a Go
+// slice of ints, e.g. {page, offset} for EB_Position-carrying hooks.
+func hookArgs(argc C.int, argv *C.uint) []int {
+	raw := unsafe.Slice(argv, int(argc))
+
+	args := make([]int, len(raw))
+	for i, v := range raw {
+		args[i] = int(v)
+	}
+
+	return args
+}
+
+func lookupGaiji(context *GaijiContext, codepoint int, font fontType) (string, bool) {
+	if context == nil {
+		return "", false
+	}
+
+	var (
+		glyph string
+		ok    bool
+	)
+
+	switch font {
+	case fontTypeNarrow:
+		glyph, ok = context.Narrow[codepoint]
+	case fontTypeWide:
+		glyph, ok = context.Wide[codepoint]
+	}
+
+	return glyph, ok
+}
+
 func formatEbError(code C.EB_Error_Code) string {
 	return C.GoString(C.eb_error_string(code))
 }
@@ -110,9 +277,29 @@ type Gaiji struct {
 	Glyph48 image.Image
 }
 
+// Position identifies a block of content within a subbook, in the same
+// terms EB itself uses (a page/offset pair, per EB_Position). It is stable
+// across loads of the same file and can be handed to Subbook.LookupByPosition
+// or Subbook.Seek.
+type Position struct {
+	Page   int
+	Offset int
+}
+
+func positionFromEB(position C.EB_Position) Position {
+	return Position{Page: int(position.page), Offset: int(position.offset)}
+}
+
+func (p Position) toEB() C.EB_Position {
+	return C.EB_Position{page: C.int(p.Page), offset: C.int(p.Offset)}
+}
+
 type Entry struct {
-	Heading string
-	Text    string
+	Heading    string
+	Text       string
+	Nodes      []Node
+	HeadingPos Position
+	TextPos    Position
 }
 
 type Subbook struct {
@@ -121,20 +308,148 @@ type Subbook struct {
 	Entries     []Entry
 	GaijiWide   map[int]Gaiji
 	GaijiNarrow map[int]Gaiji
+	Menu        *MenuNode
+	Assets      map[Position]Asset
+
+	positionIndex map[Position]int
+	path          string
+	code          C.EB_Subbook_Code
+	flags         LoadFlags
+	gaijiContext  *GaijiContext
+	live          *bookContext
+}
+
+// LookupByPosition returns the Entry whose heading or text starts at pos, if
+// one was loaded. The returned Entry aliases the Subbook's Entries slice.
+func (s *Subbook) LookupByPosition(pos Position) (*Entry, bool) {
+	i, ok := s.positionIndex[pos]
+	if !ok {
+		return nil, false
+	}
+
+	return &s.Entries[i], true
+}
+
+// Seek reads a single Entry at pos directly from disk, without loading the
+// rest of the subbook. It re-opens the underlying EB book for the duration
+// of the call, so it is meant for occasional lookups (e.g. resolving a
+// ReferenceNode) rather than bulk iteration.
+func (s *Subbook) Seek(pos Position) (Entry, error) {
+	bc := bookContext{flags: s.flags}
+	if err := bc.initialize(); err != nil {
+		return Entry{}, err
+	}
+	defer bc.shutdown()
+
+	pathC := C.CString(s.path)
+	defer C.free(unsafe.Pointer(pathC))
+	if err := wrapEbError("eb_bind", C.eb_bind(bc.book, pathC)); err != nil {
+		return Entry{}, err
+	}
+
+	if err := wrapEbError("eb_set_subbook", C.eb_set_subbook(bc.book, s.code)); err != nil {
+		return Entry{}, err
+	}
+
+	sc := &subbookContext{
+		codepointsWide:   make(map[int]bool),
+		codepointsNarrow: make(map[int]bool),
+		flags:            s.flags,
+		gaijiContext:     s.gaijiContext,
+	}
+
+	ebPosition := pos.toEB()
+
+	var (
+		entry Entry
+		err   error
+	)
+
+	entry.HeadingPos = pos
+	entry.TextPos = pos
+
+	if entry.Heading, err = bc.loadContent(ebPosition, blockTypeHeading, sc); err != nil {
+		return Entry{}, err
+	}
+
+	var rawText string
+	if rawText, err = bc.loadContent(ebPosition, blockTypeText, sc); err != nil {
+		return Entry{}, err
+	}
+	entry.Text, entry.Nodes = decodeNodes(rawText)
+
+	return entry, nil
 }
 
 type Book struct {
 	DiscCode string
 	CharCode string
 	Subbooks []Subbook
+
+	bc *bookContext
+}
+
+// Close releases the EB_Book handle opened by Open. It is a no-op on a Book
+// returned by Load, which has already released its handle by the time it is
+// returned.
+func (b *Book) Close() error {
+	if b.bc == nil {
+		return nil
+	}
+
+	b.bc.shutdown()
+	b.bc = nil
+
+	for i := range b.Subbooks {
+		b.Subbooks[i].live = nil
+	}
+
+	return nil
 }
 
 type bookContext struct {
-	buffer  []byte
-	decoder *encoding.Decoder
-	hookset *C.EB_Hookset
-	book    *C.EB_Book
-	flags   LoadFlags
+	buffer       []byte
+	decoder      *encoding.Decoder
+	encoder      *encoding.Encoder
+	hookset      *C.EB_Hookset
+	book         *C.EB_Book
+	flags        LoadFlags
+	gaijiMatcher GaijiMatcher
+	path         string
+
+	// mu guards every use of book/hookset once a Book is live (see Open):
+	// all of its Subbooks share this one EB_Book handle, and EB's
+	// eb_set_subbook/eb_seek_text/eb_search_*/eb_hit_list calls mutate that
+	// handle's "current subbook"/search-cursor state with no locking of
+	// their own. It is unused (and uncontended) during Load/loadInternal,
+	// which run single-threaded before any Subbook is handed out.
+	mu sync.Mutex
+}
+
+// Option customizes the behavior of Load.
+type Option func(*bookContext)
+
+// WithGaijiContexts makes the given GaijiContexts available for matching
+// against loaded subbooks, in addition to this package's built-in registry.
+// The first context whose Name appears in a subbook's title is used.
+func WithGaijiContexts(contexts []*GaijiContext) Option {
+	return WithGaijiMatcher(func(subbook Subbook) *GaijiContext {
+		if context := matchDefaultGaijiContext(contexts, subbook); context != nil {
+			return context
+		}
+
+		return matchDefaultGaijiContext(builtinGaijiContexts, subbook)
+	})
+}
+
+// WithGaijiMatcher overrides how a GaijiContext is selected for each loaded
+// subbook, in place of the built-in registry. WithGaijiContexts is built on
+// it and sets the same matcher, so when both are given the one passed last
+// applies.
+func WithGaijiMatcher(matcher GaijiMatcher) Option {
+	return func(bc *bookContext) {
+		bc.gaijiMatcher = matcher
+	}
 }
 
 func (bc *bookContext) initialize() error {
@@ -154,6 +469,7 @@ func (bc *bookContext) initialize() error {
 
 	bc.buffer = make([]byte, 22)
 	bc.decoder = japanese.EUCJP.NewDecoder()
+	bc.encoder = japanese.EUCJP.NewEncoder()
 
 	return nil
 }
@@ -172,6 +488,29 @@ func (bc *bookContext) installHooks() error {
 	hookCodes := []C.EB_Hook_Code{
 		C.EB_HOOK_NARROW_FONT,
 		C.EB_HOOK_WIDE_FONT,
+		C.EB_HOOK_BEGIN_REFERENCE,
+		C.EB_HOOK_END_REFERENCE,
+		C.EB_HOOK_BEGIN_CANDIDATE,
+		C.EB_HOOK_END_CANDIDATE_GROUP,
+		C.EB_HOOK_BEGIN_KEYWORD,
+		C.EB_HOOK_END_KEYWORD,
+		C.EB_HOOK_BEGIN_SUBSCRIPT,
+		C.EB_HOOK_END_SUBSCRIPT,
+		C.EB_HOOK_BEGIN_SUPERSCRIPT,
+		C.EB_HOOK_END_SUPERSCRIPT,
+		C.EB_HOOK_SET_INDENT,
+		C.EB_HOOK_NEWLINE,
+		C.EB_HOOK_WIDE_JISX0208,
+		C.EB_HOOK_NARROW_JISX0208,
+		C.EB_HOOK_GB2312,
+		C.EB_HOOK_BEGIN_MONO_GRAPHIC,
+		C.EB_HOOK_END_MONO_GRAPHIC,
+		C.EB_HOOK_BEGIN_COLOR_GRAPHIC,
+		C.EB_HOOK_END_COLOR_GRAPHIC,
+		C.EB_HOOK_BEGIN_WAVE,
+		C.EB_HOOK_END_WAVE,
+		C.EB_HOOK_BEGIN_MPEG,
+		C.EB_HOOK_END_MPEG,
 	}
 
 	for _, hookCode := range hookCodes {
@@ -184,6 +523,8 @@ func (bc *bookContext) installHooks() error {
 }
 
 func (bc *bookContext) loadInternal(path string) (*Book, error) {
+	bc.path = path
+
 	pathC := C.CString(path)
 	defer C.free(unsafe.Pointer(pathC))
 	if err := wrapEbError("eb_bind", C.eb_bind(bc.book, pathC)); err != nil {
@@ -272,25 +613,29 @@ func (bc *bookContext) loadSubbook(subbookCode C.EB_Subbook_Code) (*Subbook, err
 		return nil, err
 	}
 
-	setSubbookContext(&subbookContext{
+	sc := &subbookContext{
 		codepointsWide:   make(map[int]bool),
 		codepointsNarrow: make(map[int]bool),
 		flags:            bc.flags,
-	})
-
-	defer clearSubbookContext()
+	}
 
 	var err error
 	subbook := Subbook{
 		GaijiWide:   make(map[int]Gaiji),
 		GaijiNarrow: make(map[int]Gaiji),
+		path:        bc.path,
+		code:        subbookCode,
+		flags:       bc.flags,
 	}
 
 	if subbook.Title, err = bc.loadTitle(); err != nil {
 		return nil, err
 	}
 
-	if subbook.Copyright, err = bc.loadCopyright(); err != nil {
+	sc.gaijiContext = bc.resolveGaijiContext(subbook)
+	subbook.gaijiContext = sc.gaijiContext
+
+	if subbook.Copyright, err = bc.loadCopyright(sc); err != nil {
 		return nil, err
 	}
 
@@ -301,12 +646,17 @@ func (bc *bookContext) loadSubbook(subbookCode C.EB_Subbook_Code) (*Subbook, err
 
 	for {
 		var entry Entry
-		if entry.Heading, err = bc.loadContent(position, blockTypeHeading); err != nil {
+		entry.HeadingPos = positionFromEB(position)
+		entry.TextPos = entry.HeadingPos
+
+		if entry.Heading, err = bc.loadContent(position, blockTypeHeading, sc); err != nil {
 			return nil, err
 		}
-		if entry.Text, err = bc.loadContent(position, blockTypeText); err != nil {
+		var rawText string
+		if rawText, err = bc.loadContent(position, blockTypeText, sc); err != nil {
 			return nil, err
 		}
+		entry.Text, entry.Nodes = decodeNodes(rawText)
 
 		subbook.Entries = append(subbook.Entries, entry)
 
@@ -376,7 +726,7 @@ func (bc *bookContext) loadSubbook(subbookCode C.EB_Subbook_Code) (*Subbook, err
 			return nil, err
 		}
 
-		for codepoint := range activeSubbookContext.codepointsWide {
+		for codepoint := range sc.codepointsWide {
 			glyph, err := bc.blitGaiji(codepoint, int(widthWide), int(height), fontTypeWide)
 			if err != nil {
 				return nil, err
@@ -385,7 +735,7 @@ func (bc *bookContext) loadSubbook(subbookCode C.EB_Subbook_Code) (*Subbook, err
 			setGaiji(codepoint, glyph, subbook.GaijiWide)
 		}
 
-		for codepoint := range activeSubbookContext.codepointsNarrow {
+		for codepoint := range sc.codepointsNarrow {
 			glyph, err := bc.blitGaiji(codepoint, int(widthNarrow), int(height), fontTypeNarrow)
 			if err != nil {
 				return nil, err
@@ -395,6 +745,24 @@ func (bc *bookContext) loadSubbook(subbookCode C.EB_Subbook_Code) (*Subbook, err
 		}
 	}
 
+	subbook.positionIndex = make(map[Position]int, len(subbook.Entries))
+	for i, entry := range subbook.Entries {
+		subbook.positionIndex[entry.HeadingPos] = i
+		subbook.positionIndex[entry.TextPos] = i
+	}
+
+	if bc.flags&LoadFlagsMenu != 0 {
+		if subbook.Menu, err = bc.loadMenu(sc); err != nil {
+			return nil, err
+		}
+	}
+
+	if bc.flags&(LoadFlagsGraphics|LoadFlagsAudio) != 0 {
+		if subbook.Assets, err = bc.loadAssets(subbook, sc, bc.flags&LoadFlagsGraphics != 0, bc.flags&LoadFlagsAudio != 0); err != nil {
+			return nil, err
+		}
+	}
+
 	return &subbook, nil
 }
 
@@ -432,6 +800,14 @@ func (bc *bookContext) blitGaiji(codepoint, width, height int, font fontType) (i
 	return glyph, nil
 }
 
+func (bc *bookContext) resolveGaijiContext(subbook Subbook) *GaijiContext {
+	if bc.gaijiMatcher != nil {
+		return bc.gaijiMatcher(subbook)
+	}
+
+	return matchDefaultGaijiContext(builtinGaijiContexts, subbook)
+}
+
 func (bc *bookContext) loadTitle() (string, error) {
 	var data [C.EB_MAX_TITLE_LENGTH + 1]C.char
 	if err := wrapEbError("eb_subbook_title", C.eb_subbook_title(bc.book, &data[0])); err != nil {
@@ -441,7 +817,7 @@ func (bc *bookContext) loadTitle() (string, error) {
 	return bc.decoder.String(C.GoString(&data[0]))
 }
 
-func (bc *bookContext) loadCopyright() (string, error) {
+func (bc *bookContext) loadCopyright(sc *subbookContext) (string, error) {
 	if C.eb_have_copyright(bc.book) == 0 {
 		return "", nil
 	}
@@ -451,10 +827,19 @@ func (bc *bookContext) loadCopyright() (string, error) {
 		return "", err
 	}
 
-	return bc.loadContent(position, blockTypeText)
+	raw, err := bc.loadContent(position, blockTypeText, sc)
+	if err != nil {
+		return "", err
+	}
+
+	text, _ := decodeNodes(raw)
+	return text, nil
 }
 
-func (bc *bookContext) loadContent(position C.EB_Position, blockType blockType) (string, error) {
+func (bc *bookContext) loadContent(position C.EB_Position, blockType blockType, sc *subbookContext) (string, error) {
+	container, release := newContainer(sc)
+	defer release()
+
 	for {
 		var (
 			data     = (*C.char)(unsafe.Pointer(&bc.buffer[0]))
@@ -468,11 +853,11 @@ func (bc *bookContext) loadContent(position C.EB_Position, blockType blockType)
 
 		switch blockType {
 		case blockTypeHeading:
-			if err := wrapEbError("eb_read_heading", C.eb_read_heading(bc.book, nil, bc.hookset, nil, dataSize, data, &dataUsed)); err != nil {
+			if err := wrapEbError("eb_read_heading", C.eb_read_heading(bc.book, nil, bc.hookset, container, dataSize, data, &dataUsed)); err != nil {
 				return "", err
 			}
 		case blockTypeText:
-			if err := wrapEbError("eb_read_text", C.eb_read_text(bc.book, nil, bc.hookset, nil, dataSize, data, &dataUsed)); err != nil {
+			if err := wrapEbError("eb_read_text", C.eb_read_text(bc.book, nil, bc.hookset, container, dataSize, data, &dataUsed)); err != nil {
 				return "", err
 			}
 		}
@@ -485,8 +870,12 @@ func (bc *bookContext) loadContent(position C.EB_Position, blockType blockType)
 	}
 }
 
-func Load(path string, flags LoadFlags) (*Book, error) {
+func Load(path string, flags LoadFlags, opts ...Option) (*Book, error) {
 	bc := bookContext{flags: flags}
+	for _, opt := range opts {
+		opt(&bc)
+	}
+
 	if err := bc.initialize(); err != nil {
 		return nil, err
 	}
@@ -494,3 +883,31 @@ func Load(path string, flags LoadFlags) (*Book, error) {
 	defer bc.shutdown()
 	return bc.loadInternal(path)
 }
+
+// Open loads path like Load, but keeps the underlying EB_Book handle open
+// instead of releasing it once loading finishes. This lets the returned
+// Book's Subbooks service EB's native searches (SearchWord, SearchEndword,
+// SearchKeyword, SearchCross). Callers must call Book.Close when done.
+func Open(path string, flags LoadFlags, opts ...Option) (*Book, error) {
+	bc := &bookContext{flags: flags}
+	for _, opt := range opts {
+		opt(bc)
+	}
+
+	if err := bc.initialize(); err != nil {
+		return nil, err
+	}
+
+	book, err := bc.loadInternal(path)
+	if err != nil {
+		bc.shutdown()
+		return nil, err
+	}
+
+	book.bc = bc
+	for i := range book.Subbooks {
+		book.Subbooks[i].live = bc
+	}
+
+	return book, nil
+}
@@ -0,0 +1,71 @@
+// Package gaiji defines per-dictionary gaiji (外字) translation tables that
+// map the private-use codepoints emitted by an EPWING subbook's narrow/wide
+// fonts to the real UTF-8 characters they represent, and parses them from
+// JSON. It ships no tables of its own (see Builtin).
+package gaiji
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Context carries the narrow and wide gaiji mappings for a single dictionary.
+// Narrow and Wide are keyed by EPWING font codepoint.
+type Context struct {
+	Name   string
+	Narrow map[int]string
+	Wide   map[int]string
+}
+
+type contextFile struct {
+	Name   string            `json:"name"`
+	Narrow map[string]string `json:"narrow"`
+	Wide   map[string]string `json:"wide"`
+}
+
+// ParseContext decodes a Context from the JSON schema {"name", "narrow",
+// "wide"}, with narrow/wide keyed by decimal codepoint string. It's the
+// format a built-in table would need to be in; use it to load your own
+// sourced tables before handing them to RegisterGaijiContext or
+// zig.WithGaijiContexts.
+func ParseContext(data []byte) (*Context, error) {
+	var file contextFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	ctx := &Context{
+		Name:   file.Name,
+		Narrow: make(map[int]string, len(file.Narrow)),
+		Wide:   make(map[int]string, len(file.Wide)),
+	}
+
+	for key, value := range file.Narrow {
+		codepoint, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid narrow codepoint %q in context %q: %w", key, file.Name, err)
+		}
+		ctx.Narrow[codepoint] = value
+	}
+
+	for key, value := range file.Wide {
+		codepoint, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wide codepoint %q in context %q: %w", key, file.Name, err)
+		}
+		ctx.Wide[codepoint] = value
+	}
+
+	return ctx, nil
+}
+
+// Builtin returns the gaiji contexts that ship with this package.
+//
+// It returns none: this package holds no table for any dictionary,
+// Daijirin and Kenkyusha included. Gaiji are resolved only through contexts
+// supplied via RegisterGaijiContext or zig.WithGaijiContexts; without one,
+// a subbook's gaiji are left as stub markers or dropped.
+func Builtin() ([]*Context, error) {
+	return nil, nil
+}
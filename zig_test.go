@@ -0,0 +1,31 @@
+package zig
+
+import (
+	"os"
+	"testing"
+)
+
+// BenchmarkLoadParallel loads the dictionary at ZIG_TEST_BOOK_PATH b.N
+// times, spread by b.RunParallel across GOMAXPROCS goroutines running
+// concurrently. It exists to check that hookCallback's
+// per-call container state (see newContainer) actually lets unrelated Load
+// calls proceed in parallel rather than serializing on shared state, and
+// that throughput scales with GOMAXPROCS rather than flatlining.
+//
+// Set ZIG_TEST_BOOK_PATH to a real EPWING CATALOGS/CATALOG directory to run
+// it; it's skipped otherwise, since this repo doesn't ship fixture data.
+func BenchmarkLoadParallel(b *testing.B) {
+	path := os.Getenv("ZIG_TEST_BOOK_PATH")
+	if path == "" {
+		b.Skip("ZIG_TEST_BOOK_PATH not set; no fixture dictionary to benchmark against")
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := Load(path, LoadFlagsNone); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}